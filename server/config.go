@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default values used by LoadConfig when a setting is present in neither the config file
+// nor the environment.
+const (
+	DefaultPort    = "8080"
+	DefaultAuthor  = ""
+	DefaultColor   = "#888888"
+	DefaultHead    = "default"
+	DefaultTail    = "default"
+	DefaultVersion = "0.0.1"
+)
+
+// Config describes a battlesnake server's configuration, as loaded by LoadConfig and
+// consumed by NewFromConfig.
+type Config struct {
+	Port           string   `json:"port"`
+	LoggerOpts     []string `json:"loggerOpts"`
+	Author         string   `json:"author"`
+	Color          string   `json:"color"`
+	Head           string   `json:"head"`
+	Tail           string   `json:"tail"`
+	Version        string   `json:"version"`
+	SafetyMarginMs int      `json:"safetyMarginMs"`
+}
+
+// LoadConfig reads a JSON config file at path into a Config. LoggerOpts is specified by
+// name (e.g. ["error","warning","info"]) rather than by bitmask, see NewFromConfig.
+//
+// Any setting left unset in the file falls back to the environment variable of the same
+// name in upper snake case (e.g. PORT, SAFETY_MARGIN_MS, LOGGER_OPTS as a comma-separated
+// list), and finally to the package's exported Default* constants. That is, values are
+// resolved in the order: config file, then environment variables, then defaults.
+// SafetyMarginMs and LoggerOpts have no Default* constant and are left zero or empty
+// when set in neither the file nor the environment.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("server: could not read config file: %w", err)
+	}
+
+	cfg := Config{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("server: could not parse config file: %w", err)
+	}
+
+	if cfg.Port == "" {
+		cfg.Port = envOrDefault("PORT", DefaultPort)
+	}
+	if cfg.Author == "" {
+		cfg.Author = envOrDefault("AUTHOR", DefaultAuthor)
+	}
+	if cfg.Color == "" {
+		cfg.Color = envOrDefault("COLOR", DefaultColor)
+	}
+	if cfg.Head == "" {
+		cfg.Head = envOrDefault("HEAD", DefaultHead)
+	}
+	if cfg.Tail == "" {
+		cfg.Tail = envOrDefault("TAIL", DefaultTail)
+	}
+	if cfg.Version == "" {
+		cfg.Version = envOrDefault("VERSION", DefaultVersion)
+	}
+	if cfg.SafetyMarginMs == 0 {
+		if v := os.Getenv("SAFETY_MARGIN_MS"); v != "" {
+			ms, err := strconv.Atoi(v)
+			if err != nil {
+				return Config{}, fmt.Errorf("server: invalid SAFETY_MARGIN_MS %q: %w", v, err)
+			}
+			cfg.SafetyMarginMs = ms
+		}
+	}
+	if len(cfg.LoggerOpts) == 0 {
+		if v := os.Getenv("LOGGER_OPTS"); v != "" {
+			cfg.LoggerOpts = strings.Split(v, ",")
+		}
+	}
+
+	return cfg, nil
+}
+
+// NewFromConfig builds a BattlesnakeServer from cfg, constructing its InfoResponse and
+// Logger so callers don't have to. opts are appended after any Option implied by cfg
+// (currently WithSafetyMargin, when cfg.SafetyMarginMs is set).
+func NewFromConfig(cfg Config, moveFunc func(*GameState, *Logger) MoveResponse, opts ...Option) *BattlesnakeServer {
+	info := &InfoResponse{
+		Author:  cfg.Author,
+		Color:   cfg.Color,
+		Head:    cfg.Head,
+		Tail:    cfg.Tail,
+		Version: cfg.Version,
+	}
+
+	if cfg.SafetyMarginMs > 0 {
+		opts = append([]Option{WithSafetyMargin(time.Duration(cfg.SafetyMarginMs) * time.Millisecond)}, opts...)
+	}
+
+	logger := log.New(os.Stdout, "", log.LstdFlags)
+	return New(cfg.Port, info, logger, loggerOptsFromNames(cfg.LoggerOpts), moveFunc, opts...)
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// loggerOptsFromNames converts logger option names (e.g. "debug", "info") to the bitmask
+// accepted by New/NewWithContext. Unrecognized names are ignored.
+func loggerOptsFromNames(names []string) int {
+	opts := 0
+	for _, n := range names {
+		switch strings.ToLower(n) {
+		case "error":
+			opts |= LError
+		case "warning", "warn":
+			opts |= LWarning
+		case "info":
+			opts |= LInfo
+		case "debug":
+			opts |= LDebug
+		}
+	}
+	return opts
+}
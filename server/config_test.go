@@ -0,0 +1,107 @@
+package server_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haguro/go-battlesnake-server/server"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("FileValuesTakePriority", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+		writeConfigFile(t, path, server.Config{
+			Port:       "9000",
+			Author:     "foo",
+			Color:      "#ff0000",
+			Head:       "smile",
+			Tail:       "curled",
+			Version:    "1.2.3",
+			LoggerOpts: []string{"debug"},
+		})
+
+		t.Setenv("PORT", "9999")
+
+		cfg, err := server.LoadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if cfg.Port != "9000" {
+			t.Errorf("expected file value %q to take priority over env, got %q", "9000", cfg.Port)
+		}
+	})
+
+	t.Run("FallsBackToEnv", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+		writeConfigFile(t, path, server.Config{})
+
+		t.Setenv("PORT", "9999")
+		t.Setenv("COLOR", "#123456")
+
+		cfg, err := server.LoadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if cfg.Port != "9999" {
+			t.Errorf("expected env value %q, got %q", "9999", cfg.Port)
+		}
+		if cfg.Color != "#123456" {
+			t.Errorf("expected env value %q, got %q", "#123456", cfg.Color)
+		}
+	})
+
+	t.Run("FallsBackToDefaults", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+		writeConfigFile(t, path, server.Config{})
+
+		cfg, err := server.LoadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if cfg.Port != server.DefaultPort {
+			t.Errorf("expected default port %q, got %q", server.DefaultPort, cfg.Port)
+		}
+		if cfg.Color != server.DefaultColor {
+			t.Errorf("expected default color %q, got %q", server.DefaultColor, cfg.Color)
+		}
+	})
+
+	t.Run("FileNotFound", func(t *testing.T) {
+		if _, err := server.LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+			t.Fatal("expected an error for a missing config file, got nil")
+		}
+	})
+}
+
+func TestNewFromConfig(t *testing.T) {
+	cfg := server.Config{
+		Port:    "0",
+		Author:  "foo",
+		Color:   "#000000",
+		Head:    "default",
+		Tail:    "default",
+		Version: "9.9",
+	}
+	s := server.NewFromConfig(cfg, func(gs *server.GameState, l *server.Logger) server.MoveResponse {
+		return server.MoveResponse{Move: "up"}
+	})
+	if s == nil {
+		t.Fatal("expected a non-nil BattlesnakeServer")
+	}
+}
+
+func writeConfigFile(t *testing.T, path string, cfg server.Config) {
+	t.Helper()
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("could not marshal config: %s", err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatalf("could not write config file: %s", err)
+	}
+}
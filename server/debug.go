@@ -0,0 +1,159 @@
+package server
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	httppprof "net/http/pprof"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sync/atomic"
+	"time"
+)
+
+// debugMetrics holds the expvar counters published under /debug/vars while debug logging
+// is enabled.
+type debugMetrics struct {
+	requests     *expvar.Map
+	decodeErrors *expvar.Int
+	panics       *expvar.Int
+	moveDuration *expvar.Map
+}
+
+// moveDurationBuckets are the upper bounds of the moveFunc latency histogram published
+// under /debug/vars, in ascending order.
+var moveDurationBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// debugMetricsSeq assigns each debugMetrics instance a unique, monotonically
+// increasing id so its expvar names never collide, even if an earlier
+// *BattlesnakeServer was collected and its address reused.
+var debugMetricsSeq atomic.Int64
+
+// newDebugMetrics publishes a fresh set of expvar counters for s, namespaced by a
+// monotonic id so multiple debug-enabled servers (e.g. behind a Hub) don't collide.
+func newDebugMetrics(s *BattlesnakeServer) *debugMetrics {
+	ns := fmt.Sprintf("battlesnake_%d", debugMetricsSeq.Add(1))
+	return &debugMetrics{
+		requests:     expvar.NewMap(ns + "_requests"),
+		decodeErrors: expvar.NewInt(ns + "_decode_errors"),
+		panics:       expvar.NewInt(ns + "_panics"),
+		moveDuration: expvar.NewMap(ns + "_move_duration_ns"),
+	}
+}
+
+func (m *debugMetrics) countRequest(route string) {
+	if m == nil {
+		return
+	}
+	m.requests.Add(route, 1)
+}
+
+func (m *debugMetrics) countDecodeError() {
+	if m == nil {
+		return
+	}
+	m.decodeErrors.Add(1)
+}
+
+func (m *debugMetrics) countPanic() {
+	if m == nil {
+		return
+	}
+	m.panics.Add(1)
+}
+
+func (m *debugMetrics) recordMoveDuration(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.moveDuration.Add(bucketLabel(d), 1)
+}
+
+func bucketLabel(d time.Duration) string {
+	for _, b := range moveDurationBuckets {
+		if d <= b {
+			return "<=" + b.String()
+		}
+	}
+	return ">" + moveDurationBuckets[len(moveDurationBuckets)-1].String()
+}
+
+// registerPprof wires up net/http/pprof's handlers on r, mirroring what importing
+// net/http/pprof registers on http.DefaultServeMux.
+func registerPprof(r *http.ServeMux) {
+	r.HandleFunc("/debug/pprof/", httppprof.Index)
+	r.HandleFunc("/debug/pprof/cmdline", httppprof.Cmdline)
+	r.HandleFunc("/debug/pprof/profile", httppprof.Profile)
+	r.HandleFunc("/debug/pprof/symbol", httppprof.Symbol)
+	r.HandleFunc("/debug/pprof/trace", httppprof.Trace)
+}
+
+// registerDebugVars exposes the process's published expvar counters, including those
+// from newDebugMetrics, on r.
+func registerDebugVars(r *http.ServeMux) {
+	r.Handle("/debug/vars", expvar.Handler())
+}
+
+// WithCPUProfile makes Start write a CPU profile to path, starting when Start is called
+// and stopping when it returns.
+func WithCPUProfile(path string) Option {
+	return func(s *BattlesnakeServer) {
+		s.cpuProfilePath = path
+	}
+}
+
+// WithMemProfile makes Start write a heap profile to path when it returns.
+func WithMemProfile(path string) Option {
+	return func(s *BattlesnakeServer) {
+		s.memProfilePath = path
+	}
+}
+
+// startCPUProfile starts CPU profiling to s.cpuProfilePath, if set, and returns a func
+// that stops profiling and closes the file. It is a no-op if no path was configured.
+func (s *BattlesnakeServer) startCPUProfile() (func(), error) {
+	if s.cpuProfilePath == "" {
+		return func() {}, nil
+	}
+	f, err := os.Create(s.cpuProfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("server: could not create CPU profile file: %w", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("server: could not start CPU profile: %w", err)
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		f.Close()
+	}, nil
+}
+
+// writeMemProfile writes a heap profile to s.memProfilePath, if set. It is a no-op if
+// no path was configured.
+func (s *BattlesnakeServer) writeMemProfile() {
+	if s.memProfilePath == "" {
+		return
+	}
+	f, err := os.Create(s.memProfilePath)
+	if err != nil {
+		s.logger.Err("Failed to create memory profile file: %s", err)
+		return
+	}
+	defer f.Close()
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		s.logger.Err("Failed to write memory profile: %s", err)
+	}
+}
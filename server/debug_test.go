@@ -0,0 +1,71 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/haguro/go-battlesnake-server/server"
+)
+
+func TestDebugEndpoints(t *testing.T) {
+	info := server.InfoResponse{
+		APIVersion: "1",
+		Author:     "foo",
+		Color:      "#000000",
+		Head:       "default",
+		Tail:       "default",
+		Version:    "9.9",
+	}
+	logger := log.New(io.Discard, "", 0)
+	s := server.NewWithContext("0", &info, logger, server.LDebug, func(ctx context.Context, gs *server.GameState, l *server.Logger) server.MoveResponse {
+		return server.MoveResponse{Move: "up"}
+	})
+
+	t.Run("PprofIndex", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Errorf("expected status code %d, got %d", http.StatusOK, resp.Result().StatusCode)
+		}
+	})
+
+	t.Run("ExpvarVars", func(t *testing.T) {
+		b := bytes.NewBuffer([]byte{})
+		json.NewEncoder(b).Encode(&server.GameState{})
+		req, _ := http.NewRequest(http.MethodPost, "/start", b)
+		s.ServeHTTP(httptest.NewRecorder(), req)
+
+		req, _ = http.NewRequest(http.MethodGet, "/debug/vars", nil)
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d", http.StatusOK, resp.Result().StatusCode)
+		}
+		if !strings.Contains(resp.Body.String(), "battlesnake_") {
+			t.Errorf("expected /debug/vars to contain battlesnake metrics, got %q", resp.Body.String())
+		}
+	})
+
+	t.Run("DisabledWithoutDebugLogging", func(t *testing.T) {
+		plain := server.New("0", &info, logger, 0, func(gs *server.GameState, l *server.Logger) server.MoveResponse {
+			return server.MoveResponse{Move: "up"}
+		})
+		req, _ := http.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+		resp := httptest.NewRecorder()
+		plain.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusNotFound {
+			t.Errorf("expected status code %d, got %d", http.StatusNotFound, resp.Result().StatusCode)
+		}
+	})
+}
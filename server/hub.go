@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// Hub hosts multiple battlesnakes on a single HTTP server, each mounted under its own
+// "/snakes/{name}" path prefix, e.g. POST /snakes/aggressive/move. A GET / on the Hub
+// itself lists the names of all registered snakes as JSON.
+type Hub struct {
+	http.Handler
+	port       string
+	logger     *Logger
+	rawLogger  *log.Logger
+	loggerOpts int
+
+	mu     sync.Mutex
+	mux    *http.ServeMux
+	names  []string
+	snakes map[string]*BattlesnakeServer
+}
+
+// NewHub creates a Hub that will listen on port. logger and loggerOpts are used to
+// construct the Logger for both the Hub itself and every snake registered with it.
+func NewHub(port string, logger *log.Logger, loggerOpts int) *Hub {
+	h := &Hub{
+		port:       port,
+		logger:     NewLogger(logger, loggerOpts),
+		rawLogger:  logger,
+		loggerOpts: loggerOpts,
+		mux:        http.NewServeMux(),
+		snakes:     make(map[string]*BattlesnakeServer),
+	}
+	h.mux.HandleFunc("/", h.indexHandler())
+	h.Handler = h.mux
+	return h
+}
+
+// Register mounts a new battlesnake under the "/snakes/{name}" prefix. name must be
+// unique across the Hub's lifetime; Register returns an error if it has already been
+// used. opts are passed through to the snake's own BattlesnakeServer, allowing e.g.
+// WithStats or WithSafetyMargin to be set per snake.
+func (h *Hub) Register(name string, info *InfoResponse, moveFunc func(*GameState, *Logger) MoveResponse, opts ...Option) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.snakes[name]; ok {
+		return fmt.Errorf("server: snake %q is already registered", name)
+	}
+
+	snake := New(h.port, info, h.rawLogger, h.loggerOpts, moveFunc, opts...)
+	prefix := "/snakes/" + name
+	h.mux.Handle(prefix+"/", http.StripPrefix(prefix, snake))
+	h.snakes[name] = snake
+	h.names = append(h.names, name)
+	return nil
+}
+
+// Start listens on the Hub's port and serves all registered snakes until the process
+// exits or an error occurs.
+func (h *Hub) Start() error {
+	ln, err := net.Listen("tcp", ":"+h.port)
+	if err != nil {
+		return err
+	}
+	h.logger.Printf("START hub running at %s...\n", ln.Addr())
+	return http.Serve(ln, h)
+}
+
+func (h *Hub) indexHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		h.mu.Lock()
+		names := make([]string, len(h.names))
+		copy(names, h.names)
+		h.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(names); err != nil {
+			h.logger.Err("Failed to encode hub index response: %s", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haguro/go-battlesnake-server/server"
+)
+
+func TestHub(t *testing.T) {
+	logger := log.New(io.Discard, "", 0)
+	aggressive := server.InfoResponse{Author: "foo", Color: "#ff0000", Head: "default", Tail: "default", Version: "1.0"}
+	defensive := server.InfoResponse{Author: "foo", Color: "#0000ff", Head: "default", Tail: "default", Version: "1.0"}
+
+	hub := server.NewHub("0", logger, 0)
+	if err := hub.Register("aggressive", &aggressive, func(gs *server.GameState, l *server.Logger) server.MoveResponse {
+		return server.MoveResponse{Move: "up"}
+	}); err != nil {
+		t.Fatalf("could not register snake: %s", err)
+	}
+	if err := hub.Register("defensive", &defensive, func(gs *server.GameState, l *server.Logger) server.MoveResponse {
+		return server.MoveResponse{Move: "down"}
+	}); err != nil {
+		t.Fatalf("could not register snake: %s", err)
+	}
+
+	t.Run("DuplicateRegistration", func(t *testing.T) {
+		err := hub.Register("aggressive", &aggressive, func(gs *server.GameState, l *server.Logger) server.MoveResponse {
+			return server.MoveResponse{Move: "up"}
+		})
+		if err == nil {
+			t.Fatal("expected an error registering a duplicate snake name, got nil")
+		}
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		hub.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d", http.StatusOK, resp.Result().StatusCode)
+		}
+		var got []string
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode response body: %s", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 registered snakes, got %d", len(got))
+		}
+	})
+
+	t.Run("SnakeInfo", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/snakes/aggressive/", nil)
+		resp := httptest.NewRecorder()
+		hub.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d", http.StatusOK, resp.Result().StatusCode)
+		}
+		var got server.InfoResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode response body: %s", err)
+		}
+		if got.Color != aggressive.Color {
+			t.Errorf("expected color %q, got %q", aggressive.Color, got.Color)
+		}
+	})
+
+	t.Run("SnakeMove", func(t *testing.T) {
+		b := bytes.NewBuffer([]byte{})
+		json.NewEncoder(b).Encode(&server.GameState{})
+		req, _ := http.NewRequest(http.MethodPost, "/snakes/defensive/move", b)
+		resp := httptest.NewRecorder()
+		hub.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d", http.StatusOK, resp.Result().StatusCode)
+		}
+		var got server.MoveResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode response body: %s", err)
+		}
+		if got.Move != "down" {
+			t.Errorf("expected move %q, got %q", "down", got.Move)
+		}
+	})
+}
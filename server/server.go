@@ -8,15 +8,24 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"log"
 	"net"
 	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
 )
 
 const apiVersion = "1"
 
+// defaultSafetyMargin is subtracted from the game's timeout when computing the deadline
+// passed to moveFunc, to leave headroom for JSON encoding and network latency.
+const defaultSafetyMargin = 50 * time.Millisecond
+
 // Coord represents a coordinate on the game board.
 type Coord struct {
 	X int `json:"x"`
@@ -120,13 +129,58 @@ type MoveResponse struct {
 	Shout string `json:"shout"`
 }
 
+// MoveFunc is the signature used by user move logic. The context is derived from the
+// game's timeout and is canceled shortly before the Battlesnake engine's deadline expires,
+// allowing expensive searches to be aborted in time to respond.
+type MoveFunc func(ctx context.Context, gs *GameState, l *Logger) MoveResponse
+
 // BattlesnakeServer represents a battlesnake server instance.
 type BattlesnakeServer struct {
 	http.Handler
-	port     string
-	info     *InfoResponse
-	logger   *Logger
-	moveFunc func(*GameState, *Logger) MoveResponse
+	port         string
+	info         *InfoResponse
+	logger       *Logger
+	moveFunc     MoveFunc
+	safetyMargin time.Duration
+	lastMoveMu   sync.Mutex
+	lastMove     map[string]string
+	stats        *Stats
+	fallback     MoveResponse
+
+	debug          *debugMetrics
+	cpuProfilePath string
+	memProfilePath string
+}
+
+// Option configures optional behaviour of a BattlesnakeServer. Options are applied in the
+// order passed to New or NewWithContext.
+type Option func(*BattlesnakeServer)
+
+// WithSafetyMargin overrides the default safety margin subtracted from the game's timeout
+// when computing the move deadline passed to moveFunc. The default is 50ms.
+func WithSafetyMargin(d time.Duration) Option {
+	return func(s *BattlesnakeServer) {
+		s.safetyMargin = d
+	}
+}
+
+// WithFallbackMove overrides the MoveResponse returned when moveFunc misses its deadline
+// or panics and no move has yet been recorded for that game. The default is {Move: "up"}.
+func WithFallbackMove(m MoveResponse) Option {
+	return func(s *BattlesnakeServer) {
+		s.fallback = m
+	}
+}
+
+// WithStats enables the in-memory game statistics subsystem, which tracks per-game turn
+// count, move distribution and moveFunc latency, and serves them on the GET /stats and
+// GET /stats/{gameID} routes. It is disabled (and the routes unregistered) by default.
+func WithStats(enabled bool) Option {
+	return func(s *BattlesnakeServer) {
+		if enabled {
+			s.stats = NewStats()
+		}
+	}
 }
 
 // New creates a new instance of the battlesnake server with the specified port, InfoResponse, logger, loggerOpts, and moveFunc.
@@ -138,6 +192,7 @@ type BattlesnakeServer struct {
 // - logger: A pointer to a log.Logger instance for to be used for logging.
 // - loggerOpts: An integer representing the logging level options. Use bitwise or `|` to combine options. e.g. `LWarn|LErr|LDebug`.
 // - moveFunc: A function that takes a pointer to a GameState and a pointer to a Logger, and returns a MoveResponse.
+// - opts: Optional Option values to further configure the server, e.g. WithSafetyMargin.
 //
 // Returns:
 // - A pointer to a BattlesnakeServer instance.
@@ -153,19 +208,47 @@ type BattlesnakeServer struct {
 //	    Version:    "0.0.1",
 //	}
 //	server := server.New("8080", info, log.New(os.Stdout, "", 0), 0, moveFunc)
-func New(port string, info *InfoResponse, logger *log.Logger, loggerOpts int, moveFunc func(*GameState, *Logger) MoveResponse) *BattlesnakeServer {
+//
+// If moveFunc needs to react to the game's move deadline, use NewWithContext instead.
+func New(port string, info *InfoResponse, logger *log.Logger, loggerOpts int, moveFunc func(*GameState, *Logger) MoveResponse, opts ...Option) *BattlesnakeServer {
+	return NewWithContext(port, info, logger, loggerOpts, func(_ context.Context, gs *GameState, l *Logger) MoveResponse {
+		return moveFunc(gs, l)
+	}, opts...)
+}
+
+// NewWithContext is identical to New except that moveFunc is passed a context.Context
+// derived from the game's timeout (GameState.Game.Timeout), canceled shortly before the
+// Battlesnake engine's deadline so expensive searches can be aborted in time to respond.
+func NewWithContext(port string, info *InfoResponse, logger *log.Logger, loggerOpts int, moveFunc MoveFunc, opts ...Option) *BattlesnakeServer {
 	info.APIVersion = apiVersion
 	s := &BattlesnakeServer{
-		port:     port,
-		info:     info,
-		logger:   NewLogger(logger, loggerOpts),
-		moveFunc: moveFunc,
+		port:         port,
+		info:         info,
+		logger:       NewLogger(logger, loggerOpts),
+		moveFunc:     moveFunc,
+		safetyMargin: defaultSafetyMargin,
+		lastMove:     make(map[string]string),
+		fallback:     MoveResponse{Move: "up"},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.logger.Enabled(LDebug) {
+		s.debug = newDebugMetrics(s)
 	}
 	r := http.NewServeMux()
 	r.HandleFunc("/", s.withRequestLogging(s.indexHandler()))
 	r.HandleFunc("/start", s.withRequestLogging(s.startHandler()))
 	r.HandleFunc("/end", s.withRequestLogging(s.endHandler()))
 	r.HandleFunc("/move", s.withRequestLogging(s.moveHandler()))
+	if s.stats != nil {
+		r.HandleFunc("/stats", s.withRequestLogging(s.statsHandler()))
+		r.HandleFunc("/stats/", s.withRequestLogging(s.statsGameHandler()))
+	}
+	if s.debug != nil {
+		registerPprof(r)
+		registerDebugVars(r)
+	}
 	s.Handler = r
 	return s
 }
@@ -175,6 +258,14 @@ func (s *BattlesnakeServer) Start() error {
 	if err != nil {
 		return err
 	}
+
+	stopCPUProfile, err := s.startCPUProfile()
+	if err != nil {
+		return err
+	}
+	defer stopCPUProfile()
+	defer s.writeMemProfile()
+
 	s.logger.Printf("START server running at %s...\n", ln.Addr())
 	s.logger.Debug("request debug logging enabled")
 	return http.Serve(ln, s)
@@ -200,9 +291,13 @@ func (s *BattlesnakeServer) startHandler() http.HandlerFunc {
 		state := &GameState{}
 		if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
 			s.logger.Err("Failed to decode start request body: %s", err)
+			s.debug.countDecodeError()
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+		if s.stats != nil {
+			s.stats.start(state.Game.ID)
+		}
 		s.logger.Info("Game ID %s [Turn %d] Snake ID %s - Start", state.Game.ID, state.Turn, state.You.ID)
 	}
 }
@@ -212,9 +307,13 @@ func (s *BattlesnakeServer) endHandler() http.HandlerFunc {
 		state := &GameState{}
 		if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
 			s.logger.Err("Failed to decode end request body: %s", err)
+			s.debug.countDecodeError()
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+		if s.stats != nil {
+			s.stats.end(state.Game.ID, state.Turn, state.You.Length)
+		}
 		s.logger.Info("Game ID %s [Turn %d] Snake ID %s - End", state.Game.ID, state.Turn, state.You.ID)
 	}
 }
@@ -224,11 +323,45 @@ func (s *BattlesnakeServer) moveHandler() http.HandlerFunc {
 		state := &GameState{}
 		if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
 			s.logger.Err("Failed to decode move request body: %s", err)
+			s.debug.countDecodeError()
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
 
-		resp := s.moveFunc(state, s.logger)
+		ctx := r.Context()
+		if state.Game.Timeout > 0 {
+			var cancel context.CancelFunc
+			deadline := time.Now().Add(time.Duration(state.Game.Timeout)*time.Millisecond - s.safetyMargin)
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+
+		start := time.Now()
+		resultCh := make(chan MoveResponse, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					s.logger.Err("Game ID %s [Turn %d] Snake ID %s - moveFunc panicked: %v\n%s", state.Game.ID, state.Turn, state.You.ID, r, debug.Stack())
+					s.debug.countPanic()
+					resultCh <- s.fallbackMove(state.Game.ID)
+				}
+			}()
+			resultCh <- s.moveFunc(ctx, state, s.logger)
+		}()
+
+		var resp MoveResponse
+		select {
+		case resp = <-resultCh:
+		case <-ctx.Done():
+			resp = s.fallbackMove(state.Game.ID)
+			s.logger.Warn("Game ID %s [Turn %d] Snake ID %s - moveFunc missed its deadline, falling back to %q", state.Game.ID, state.Turn, state.You.ID, resp.Move)
+		}
+		latency := time.Since(start)
+		s.rememberMove(state.Game.ID, resp.Move)
+		if s.stats != nil {
+			s.stats.recordMove(state.Game.ID, resp.Move, latency)
+		}
+		s.debug.recordMoveDuration(latency)
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
@@ -240,9 +373,62 @@ func (s *BattlesnakeServer) moveHandler() http.HandlerFunc {
 	}
 }
 
+// fallbackMove returns the last move successfully produced for gameID, or s.fallback if
+// no move has been recorded for that game yet.
+func (s *BattlesnakeServer) fallbackMove(gameID string) MoveResponse {
+	s.lastMoveMu.Lock()
+	defer s.lastMoveMu.Unlock()
+	if move, ok := s.lastMove[gameID]; ok {
+		return MoveResponse{Move: move}
+	}
+	return s.fallback
+}
+
+// rememberMove records move as the most recent move produced for gameID, so it can be
+// used as a fallback if a later turn misses its deadline.
+func (s *BattlesnakeServer) rememberMove(gameID, move string) {
+	s.lastMoveMu.Lock()
+	defer s.lastMoveMu.Unlock()
+	s.lastMove[gameID] = move
+}
+
+func (s *BattlesnakeServer) statsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(s.stats.All()); err != nil {
+			s.logger.Err("Failed to encode stats response: %s", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func (s *BattlesnakeServer) statsGameHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		gameID := strings.TrimPrefix(r.URL.Path, "/stats/")
+		game, ok := s.stats.Get(gameID)
+		if gameID == "" || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(game); err != nil {
+			s.logger.Err("Failed to encode stats response: %s", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 func (s *BattlesnakeServer) withRequestLogging(next http.HandlerFunc) http.HandlerFunc {
 	if s.logger.Enabled(LDebug) {
 		return func(w http.ResponseWriter, r *http.Request) {
+			s.debug.countRequest(r.URL.Path)
+
 			b, err := io.ReadAll(r.Body)
 			if err != nil {
 				s.logger.Err("Failed to read request body: %s", err)
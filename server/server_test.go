@@ -2,6 +2,7 @@ package server_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -161,6 +162,78 @@ func TestServer(t *testing.T) {
 
 	})
 
+	t.Run("MoveHandlerDeadlineExceeded", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		s := server.NewWithContext("0", &info, logger, 0, func(ctx context.Context, gs *server.GameState, l *server.Logger) server.MoveResponse {
+			<-block
+			return moveResp
+		})
+
+		state := &server.GameState{Game: server.Game{Timeout: 1}}
+		b := bytes.NewBuffer([]byte{})
+		json.NewEncoder(b).Encode(state)
+		req, _ := http.NewRequest(http.MethodPost, "/move", b)
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Errorf("expected status code %d, got %d", http.StatusOK, resp.Result().StatusCode)
+		}
+
+		var got server.MoveResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode response body %q: %s", resp.Body.String(), err)
+		}
+		if got.Move != "up" {
+			t.Errorf("expected fallback move %q, got %q", "up", got.Move)
+		}
+	})
+
+	t.Run("MoveHandlerRecoversFromPanic", func(t *testing.T) {
+		s := server.New("0", &info, logger, 0, func(gs *server.GameState, l *server.Logger) server.MoveResponse {
+			panic("boom")
+		})
+
+		b := bytes.NewBuffer([]byte{})
+		json.NewEncoder(b).Encode(&server.GameState{})
+		req, _ := http.NewRequest(http.MethodPost, "/move", b)
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Errorf("expected status code %d, got %d", http.StatusOK, resp.Result().StatusCode)
+		}
+
+		var got server.MoveResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode response body %q: %s", resp.Body.String(), err)
+		}
+		if got.Move != "up" {
+			t.Errorf("expected fallback move %q, got %q", "up", got.Move)
+		}
+	})
+
+	t.Run("WithFallbackMove", func(t *testing.T) {
+		s := server.New("0", &info, logger, 0, func(gs *server.GameState, l *server.Logger) server.MoveResponse {
+			panic("boom")
+		}, server.WithFallbackMove(server.MoveResponse{Move: "left"}))
+
+		b := bytes.NewBuffer([]byte{})
+		json.NewEncoder(b).Encode(&server.GameState{})
+		req, _ := http.NewRequest(http.MethodPost, "/move", b)
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+
+		var got server.MoveResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode response body %q: %s", resp.Body.String(), err)
+		}
+		if got.Move != "left" {
+			t.Errorf("expected fallback move %q, got %q", "left", got.Move)
+		}
+	})
+
 	t.Run("InvalidURL", func(t *testing.T) {
 		req, _ := http.NewRequest(http.MethodGet, "/does-not-exist", nil)
 		resp := httptest.NewRecorder()
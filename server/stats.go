@@ -0,0 +1,143 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxRecentGames bounds the number of games retained by Stats, evicting the oldest
+// by creation order (whether or not it ever reached /end), so a long-running server
+// doesn't accumulate unbounded memory from disconnects or crashed matches.
+const maxRecentGames = 100
+
+// MoveStats summarizes move activity for a single game.
+type MoveStats struct {
+	Total int            `json:"total"`
+	ByDir map[string]int `json:"byDirection"`
+}
+
+// GameStats holds the data accumulated for a single game across /start, /move and /end.
+type GameStats struct {
+	GameID     string        `json:"gameId"`
+	Turns      int           `json:"turns"`
+	Moves      MoveStats     `json:"moves"`
+	AvgLatency time.Duration `json:"avgLatencyNs"`
+	P95Latency time.Duration `json:"p95LatencyNs"`
+	FinalTurn  int           `json:"finalTurn"`
+	Length     int           `json:"length"`
+	Ended      bool          `json:"ended"`
+
+	latencies []time.Duration
+}
+
+// Stats tracks per-game statistics across /start, /move and /end requests: turn count,
+// move distribution, average and p95 moveFunc latency, and the final turn/length reported
+// by /end. A Stats is safe for concurrent use by multiple goroutines.
+type Stats struct {
+	mu     sync.Mutex
+	games  map[string]*GameStats
+	recent []string
+}
+
+// NewStats creates an empty Stats instance.
+func NewStats() *Stats {
+	return &Stats{games: make(map[string]*GameStats)}
+}
+
+func (st *Stats) gameLocked(gameID string) *GameStats {
+	g, ok := st.games[gameID]
+	if !ok {
+		g = &GameStats{GameID: gameID, Moves: MoveStats{ByDir: make(map[string]int)}}
+		st.games[gameID] = g
+
+		st.recent = append(st.recent, gameID)
+		if len(st.recent) > maxRecentGames {
+			delete(st.games, st.recent[0])
+			st.recent = st.recent[1:]
+		}
+	}
+	return g
+}
+
+func (st *Stats) start(gameID string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.gameLocked(gameID)
+}
+
+func (st *Stats) recordMove(gameID, move string, latency time.Duration) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	g := st.gameLocked(gameID)
+	g.Turns++
+	g.Moves.Total++
+	g.Moves.ByDir[move]++
+	g.latencies = append(g.latencies, latency)
+	g.AvgLatency, g.P95Latency = latencyStats(g.latencies)
+}
+
+func (st *Stats) end(gameID string, finalTurn, length int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	g := st.gameLocked(gameID)
+	g.FinalTurn = finalTurn
+	g.Length = length
+	g.Ended = true
+}
+
+// All returns a snapshot of every currently tracked game's stats.
+func (st *Stats) All() []GameStats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	out := make([]GameStats, 0, len(st.games))
+	for _, g := range st.games {
+		out = append(out, g.snapshot())
+	}
+	return out
+}
+
+// snapshot returns a copy of g whose move distribution map is not shared with g.
+func (g *GameStats) snapshot() GameStats {
+	c := *g
+	c.Moves.ByDir = make(map[string]int, len(g.Moves.ByDir))
+	for dir, n := range g.Moves.ByDir {
+		c.Moves.ByDir[dir] = n
+	}
+	c.latencies = nil
+	return c
+}
+
+// Get returns a snapshot of a single game's stats, and false if gameID isn't tracked.
+func (st *Stats) Get(gameID string) (GameStats, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	g, ok := st.games[gameID]
+	if !ok {
+		return GameStats{}, false
+	}
+	return g.snapshot(), true
+}
+
+// latencyStats returns the mean and 95th percentile of ds.
+func latencyStats(ds []time.Duration) (avg, p95 time.Duration) {
+	if len(ds) == 0 {
+		return 0, 0
+	}
+	sorted := make([]time.Duration, len(ds))
+	copy(sorted, ds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	avg = sum / time.Duration(len(ds))
+
+	idx := int(float64(len(sorted)) * 0.95)
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	p95 = sorted[idx]
+	return avg, p95
+}
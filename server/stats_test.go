@@ -0,0 +1,147 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haguro/go-battlesnake-server/server"
+)
+
+// postTo posts state as JSON to path on s and returns the recorded response.
+func postTo(s http.Handler, path string, state *server.GameState) *httptest.ResponseRecorder {
+	b := bytes.NewBuffer([]byte{})
+	json.NewEncoder(b).Encode(state)
+	req, _ := http.NewRequest(http.MethodPost, path, b)
+	resp := httptest.NewRecorder()
+	s.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestStats(t *testing.T) {
+	info := server.InfoResponse{
+		APIVersion: "1",
+		Author:     "foo",
+		Color:      "#000000",
+		Head:       "default",
+		Tail:       "default",
+		Version:    "9.9",
+	}
+	logger := log.New(io.Discard, "", 0)
+	s := server.NewWithContext("0", &info, logger, 0, func(ctx context.Context, gs *server.GameState, l *server.Logger) server.MoveResponse {
+		return server.MoveResponse{Move: "right"}
+	}, server.WithStats(true))
+
+	post := func(path string, state *server.GameState) *httptest.ResponseRecorder {
+		return postTo(s, path, state)
+	}
+
+	gameID := "game-1"
+	post("/start", &server.GameState{Game: server.Game{ID: gameID}})
+	post("/move", &server.GameState{Game: server.Game{ID: gameID}, Turn: 1})
+	post("/move", &server.GameState{Game: server.Game{ID: gameID}, Turn: 2})
+	post("/end", &server.GameState{Game: server.Game{ID: gameID}, Turn: 2, You: server.Battlesnake{Length: 3}})
+
+	t.Run("StatsHandler", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/stats", nil)
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d", http.StatusOK, resp.Result().StatusCode)
+		}
+		var got []server.GameStats
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode response body: %s", err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("expected 1 tracked game, got %d", len(got))
+		}
+	})
+
+	t.Run("StatsGameHandler", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/stats/"+gameID, nil)
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d", http.StatusOK, resp.Result().StatusCode)
+		}
+		var got server.GameStats
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode response body: %s", err)
+		}
+		if got.GameID != gameID {
+			t.Errorf("expected game ID %q, got %q", gameID, got.GameID)
+		}
+		if got.Moves.Total != 2 {
+			t.Errorf("expected 2 total moves, got %d", got.Moves.Total)
+		}
+		if got.Moves.ByDir["right"] != 2 {
+			t.Errorf("expected 2 'right' moves, got %d", got.Moves.ByDir["right"])
+		}
+		if !got.Ended {
+			t.Errorf("expected game to be marked ended")
+		}
+		if got.FinalTurn != 2 {
+			t.Errorf("expected final turn 2, got %d", got.FinalTurn)
+		}
+		if got.Length != 3 {
+			t.Errorf("expected length 3, got %d", got.Length)
+		}
+	})
+
+	t.Run("StatsGameHandlerNotFound", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/stats/does-not-exist", nil)
+		resp := httptest.NewRecorder()
+		s.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusNotFound {
+			t.Errorf("expected status code %d, got %d", http.StatusNotFound, resp.Result().StatusCode)
+		}
+	})
+
+	t.Run("StatsEvictsUnendedGames", func(t *testing.T) {
+		s := server.NewWithContext("0", &info, logger, 0, func(ctx context.Context, gs *server.GameState, l *server.Logger) server.MoveResponse {
+			return server.MoveResponse{Move: "right"}
+		}, server.WithStats(true))
+
+		tracked := func(gameID string) bool {
+			req, _ := http.NewRequest(http.MethodGet, "/stats/"+gameID, nil)
+			resp := httptest.NewRecorder()
+			s.ServeHTTP(resp, req)
+			return resp.Result().StatusCode == http.StatusOK
+		}
+
+		const games = 150
+		for i := 0; i < games; i++ {
+			postTo(s, "/start", &server.GameState{Game: server.Game{ID: fmt.Sprintf("unended-%d", i)}})
+		}
+
+		if tracked("unended-0") {
+			t.Errorf("expected oldest unended game to have been evicted")
+		}
+		if !tracked(fmt.Sprintf("unended-%d", games-1)) {
+			t.Errorf("expected most recent unended game to still be tracked")
+		}
+	})
+
+	t.Run("StatsDisabledByDefault", func(t *testing.T) {
+		plain := server.New("0", &info, logger, 0, func(gs *server.GameState, l *server.Logger) server.MoveResponse {
+			return server.MoveResponse{Move: "up"}
+		})
+		req, _ := http.NewRequest(http.MethodGet, "/stats", nil)
+		resp := httptest.NewRecorder()
+		plain.ServeHTTP(resp, req)
+
+		if resp.Result().StatusCode != http.StatusNotFound {
+			t.Errorf("expected status code %d, got %d", http.StatusNotFound, resp.Result().StatusCode)
+		}
+	})
+}